@@ -0,0 +1,132 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster is the CLI's client for a real, cloud-hosted cluster: every call here talks to
+// the operator over HTTP, so this package (and by extension the CLI) never needs to import the
+// operator's server-internal packages.
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// OperatorConfig holds what's needed to reach a single cluster's operator over HTTP.
+type OperatorConfig struct {
+	OperatorURL string
+	ClientID    string
+}
+
+func (c OperatorConfig) post(path string, body interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Post(c.OperatorURL+path, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("operator returned status %d for %s", resp.StatusCode, path)
+	}
+	return resp, nil
+}
+
+// Refresh asks the operator to roll out api using opts, streaming each schema.RefreshProgressEvent
+// the operator reports to onProgress as the rollout advances.
+func Refresh(operatorConfig OperatorConfig, apiName string, opts schema.RefreshOptions, onProgress func(schema.RefreshProgressEvent)) (schema.RefreshResponse, error) {
+	resp, err := operatorConfig.post("/refresh/"+apiName, opts)
+	if err != nil {
+		return schema.RefreshResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	// The operator streams newline-delimited JSON: zero or more schema.RefreshProgressEvent lines
+	// while the rollout bakes, followed by a single schema.RefreshResponse line.
+	var refreshResponse schema.RefreshResponse
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var event schema.RefreshProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err == nil && event.Stage != "" {
+			onProgress(event)
+			continue
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &refreshResponse); err != nil {
+			return schema.RefreshResponse{}, err
+		}
+	}
+
+	return refreshResponse, scanner.Err()
+}
+
+type planRequest struct {
+	ConfigPath     string            `json:"config_path"`
+	ProjectFileMap map[string][]byte `json:"project_file_map"`
+}
+
+// Plan asks the operator for a cluster-wide capacity plan covering the apis defined at
+// configPath, without deploying them.
+func Plan(operatorConfig OperatorConfig, configPath string, projectFileMap map[string][]byte) (*schema.Plan, error) {
+	resp, err := operatorConfig.post("/plan", planRequest{ConfigPath: configPath, ProjectFileMap: projectFileMap})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var plan schema.Plan
+	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+type validateRequest struct {
+	APIs []userconfig.API `json:"apis"`
+}
+
+type validateResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Validate asks the operator to run its registered validator chain (webhooks and any
+// cluster-registered OPA policies; the built-in compute and endpoint checks are not part of that
+// chain) against apis. It does not cover a user-supplied
+// --policy-file, which `cortex validate` checks locally since the operator has no access to a
+// file that only exists on the caller's machine.
+func Validate(operatorConfig OperatorConfig, apis []userconfig.API) error {
+	resp, err := operatorConfig.post("/validate", validateRequest{APIs: apis})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result validateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Error != "" {
+		return fmt.Errorf(result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"gopkg.in/yaml.v2"
+)
+
+const _defaultConfigFileName = "cortex.yaml"
+
+// getConfigPath returns the config file to validate/deploy: the first positional arg if given,
+// otherwise _defaultConfigFileName in the current directory.
+func getConfigPath(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return _defaultConfigFileName
+}
+
+// MustGetProjectFileMap reads every file alongside configPath into memory, keyed by file name, so
+// it can be shipped to the operator alongside the parsed apis.
+func MustGetProjectFileMap(configPath string) map[string][]byte {
+	projectDir := filepath.Dir(configPath)
+
+	entries, err := ioutil.ReadDir(projectDir)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	projectFileMap := map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		contents, err := ioutil.ReadFile(filepath.Join(projectDir, entry.Name()))
+		if err != nil {
+			exit.Error(err)
+		}
+		projectFileMap[entry.Name()] = contents
+	}
+
+	return projectFileMap
+}
+
+// MustGetAPIsFromProjectFileMap parses the cortex config file at configPath into its api
+// definitions.
+func MustGetAPIsFromProjectFileMap(configPath string, projectFileMap map[string][]byte) []userconfig.API {
+	contents, ok := projectFileMap[filepath.Base(configPath)]
+	if !ok {
+		exit.Error(fmt.Errorf("%s: not found", configPath))
+	}
+
+	var apis []userconfig.API
+	if err := yaml.Unmarshal(contents, &apis); err != nil {
+		exit.Error(err)
+	}
+
+	return apis
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// _flagEnv is the --env flag shared by every provider-aware command.
+var _flagEnv string
+
+// addEnvFlag registers the --env flag on cmd, defaulting it to defaultProvider.
+func addEnvFlag(cmd *cobra.Command, defaultProvider string) {
+	cmd.PersistentFlags().StringVarP(&_flagEnv, "env", "e", defaultProvider, "environment to use")
+}
+
+// Environment is a named target the CLI can deploy to: a local Docker-based cluster, or a real
+// cloud-hosted one reachable through its operator.
+type Environment struct {
+	Name        string
+	Provider    types.ProviderType
+	OperatorURL string
+}
+
+// MustReadOrConfigureEnv resolves envName to its Environment. Environment persistence (reading
+// `cortex configure`'d clusters back off disk) isn't part of this build, so envName is taken at
+// face value as the provider type itself (e.g. "local" or "aws"), which is enough for the
+// commands that only branch on env.Provider.
+func MustReadOrConfigureEnv(envName string) Environment {
+	return Environment{
+		Name:     envName,
+		Provider: types.ProviderType(envName),
+	}
+}
+
+// MustGetOperatorConfig resolves the OperatorConfig to use for the named environment.
+func MustGetOperatorConfig(envName string) cluster.OperatorConfig {
+	return cluster.OperatorConfig{OperatorURL: envName}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addEnvFlag(_planCmd, types.AWSProviderType.String())
+}
+
+var _planCmd = &cobra.Command{
+	Use:   "plan [CONFIG_FILE]",
+	Short: "show a capacity plan for the apis in a config file without deploying them",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.plan")
+
+		configPath := getConfigPath(args)
+		projectFileMap := MustGetProjectFileMap(configPath)
+
+		env := MustReadOrConfigureEnv(_flagEnv)
+		plan, err := cluster.Plan(MustGetOperatorConfig(env.Name), configPath, projectFileMap)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.CapacityPlan(plan)
+	},
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package cmd
 
 import (
+	"time"
+
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
@@ -27,31 +29,51 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var _flagRefreshForce bool
+var (
+	_flagRefreshForce          bool
+	_flagRefreshStrategy       string
+	_flagRefreshImage          string
+	_flagRefreshCanaryWeight   int
+	_flagRefreshCanaryDuration time.Duration
+	_flagRefreshRollbackOnErr  bool
+)
 
 func init() {
 	_refreshCmd.PersistentFlags().BoolVarP(&_flagRefreshForce, "force", "f", false, "override the in-progress api update")
+	_refreshCmd.PersistentFlags().StringVar(&_flagRefreshStrategy, "strategy", schema.RefreshStrategyRolling, "rollout strategy to use: rolling, canary, or blue-green")
+	_refreshCmd.PersistentFlags().StringVar(&_flagRefreshImage, "image", "", "image for the shadow deployment to run; required for canary/blue-green")
+	_refreshCmd.PersistentFlags().IntVar(&_flagRefreshCanaryWeight, "canary-weight", 10, "percentage of traffic to shift to the new version during the canary bake window")
+	_refreshCmd.PersistentFlags().DurationVar(&_flagRefreshCanaryDuration, "canary-duration", 5*time.Minute, "how long to bake the canary before promoting it")
+	_refreshCmd.PersistentFlags().BoolVar(&_flagRefreshRollbackOnErr, "rollback-on-error", true, "automatically restore the previous version if the SLO check fails during the bake window")
 	addEnvFlag(_refreshCmd, types.LocalProviderType.String())
 }
 
 var _refreshCmd = &cobra.Command{
 	Use:   "refresh API_NAME",
-	Short: "restart all replicas for an api (witout downtime)",
+	Short: "update the replicas for an api (without downtime)",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		telemetry.Event("cli.refresh")
+		telemetry.Event("cli.refresh", map[string]interface{}{"strategy": _flagRefreshStrategy})
+
+		refreshOpts := schema.RefreshOptions{
+			Force:           _flagRefreshForce,
+			Strategy:        _flagRefreshStrategy,
+			NewImage:        _flagRefreshImage,
+			CanaryWeight:    _flagRefreshCanaryWeight,
+			CanaryDuration:  _flagRefreshCanaryDuration,
+			RollbackOnError: _flagRefreshRollbackOnErr,
+		}
 
 		env := MustReadOrConfigureEnv(_flagEnv)
 		var refreshResponse schema.RefreshResponse
 		var err error
 		if env.Provider == types.AWSProviderType {
-			refreshResponse, err = cluster.Refresh(MustGetOperatorConfig(env.Name), args[0], _flagRefreshForce)
+			refreshResponse, err = cluster.Refresh(MustGetOperatorConfig(env.Name), args[0], refreshOpts, renderRefreshProgress)
 			if err != nil {
 				exit.Error(err)
 			}
 		} else {
-			// TODO show that flags are being ignored?
-			refreshResponse, err = local.Refresh(args[0])
+			refreshResponse, err = local.Refresh(args[0], refreshOpts)
 			if err != nil {
 				exit.Error(err)
 			}
@@ -60,3 +82,9 @@ var _refreshCmd = &cobra.Command{
 		print.BoldFirstLine(refreshResponse.Message)
 	},
 }
+
+// renderRefreshProgress is streamed one schema.RefreshProgressEvent at a time while a
+// rolling/canary/blue-green rollout bakes, and renders it as a live progress bar.
+func renderRefreshProgress(event schema.RefreshProgressEvent) {
+	print.Progress(event.Stage, event.PercentComplete, event.Message)
+}
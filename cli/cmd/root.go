@@ -0,0 +1,36 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cmd wires up the cortex CLI's cobra commands.
+package cmd
+
+import "github.com/spf13/cobra"
+
+var _rootCmd = &cobra.Command{
+	Use:   "cortex",
+	Short: "deploy machine learning models in production",
+}
+
+func init() {
+	_rootCmd.AddCommand(_refreshCmd)
+	_rootCmd.AddCommand(_planCmd)
+	_rootCmd.AddCommand(_validateCmd)
+}
+
+// Execute runs the cortex CLI.
+func Execute() error {
+	return _rootCmd.Execute()
+}
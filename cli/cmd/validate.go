@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/policy"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var _flagValidatePolicyFile string
+
+func init() {
+	_validateCmd.PersistentFlags().StringVar(&_flagValidatePolicyFile, "policy-file", "", "run an additional OPA/Rego policy file against the apis before submission")
+	addEnvFlag(_validateCmd, types.AWSProviderType.String())
+}
+
+var _validateCmd = &cobra.Command{
+	Use:   "validate [CONFIG_FILE]",
+	Short: "validate the apis in a config file, including any configured policy validators",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.validate")
+
+		configPath := getConfigPath(args)
+		projectFileMap := MustGetProjectFileMap(configPath)
+		apis := MustGetAPIsFromProjectFileMap(configPath, projectFileMap)
+
+		// A --policy-file only exists on the caller's machine, so it's checked locally rather
+		// than submitted to the operator. The cluster's own registered webhooks/OPA policies are
+		// enforced server-side by cluster.Validate below.
+		if _flagValidatePolicyFile != "" {
+			validator, err := policy.NewOPAValidator(_flagValidatePolicyFile)
+			if err != nil {
+				exit.Error(err)
+			}
+			for i := range apis {
+				if err := validator.Validate(&apis[i], apis); err != nil {
+					exit.Error(err)
+				}
+			}
+		}
+
+		env := MustReadOrConfigureEnv(_flagEnv)
+		if err := cluster.Validate(MustGetOperatorConfig(env.Name), apis); err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine("all apis are valid")
+	},
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package local is the CLI's client for a local, Docker-based environment that has no operator
+// to talk to.
+package local
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+// ErrorStrategyNotSupportedForProvider is returned when a refresh strategy other than the
+// default rolling restart is requested against the local provider: there's no Istio
+// VirtualService to shift traffic on, so canary and blue-green have nothing to attach to.
+func ErrorStrategyNotSupportedForProvider(strategy string) error {
+	return fmt.Errorf("%s: refresh strategy is not supported for the local provider; only the default rolling restart is available", strategy)
+}
+
+// Refresh restarts api's local containers. The local provider has no traffic-shifting mechanism,
+// so any strategy other than the default rolling restart is rejected outright rather than
+// silently ignored.
+func Refresh(apiName string, opts schema.RefreshOptions) (schema.RefreshResponse, error) {
+	if opts.Strategy != "" && opts.Strategy != schema.RefreshStrategyRolling {
+		return schema.RefreshResponse{}, ErrorStrategyNotSupportedForProvider(opts.Strategy)
+	}
+
+	return schema.RefreshResponse{Message: "restarted " + apiName}, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors provides a thin wrapper over the standard error type that lets callers prefix
+// an error with a chain of identifying strings (e.g. an api name and the config key that failed)
+// without losing the original error.
+package errors
+
+import (
+	"strings"
+)
+
+// wrappedError prefixes an underlying error with a slice of string segments, rendered joined by
+// ": ", matching how the rest of the codebase formats identify-and-wrap error chains.
+type wrappedError struct {
+	segments []string
+	cause    error
+}
+
+func (e *wrappedError) Error() string {
+	if len(e.segments) == 0 {
+		return e.cause.Error()
+	}
+	return strings.Join(e.segments, ": ") + ": " + e.cause.Error()
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.cause
+}
+
+// Wrap prefixes err with the given segments, e.g. errors.Wrap(err, api.Identify(), "compute").
+// A nil err returns nil so callers can wrap unconditionally after an `if err != nil` check.
+func Wrap(err error, segments ...string) error {
+	if err == nil {
+		return nil
+	}
+	return &wrappedError{segments: segments, cause: err}
+}
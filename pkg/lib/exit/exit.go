@@ -0,0 +1,29 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exit is the CLI's standard way of ending a command early on a fatal error.
+package exit
+
+import (
+	"fmt"
+	"os"
+)
+
+// Error prints err to stderr and exits the process with a non-zero status.
+func Error(err error) {
+	fmt.Fprintln(os.Stderr, err.Error())
+	os.Exit(1)
+}
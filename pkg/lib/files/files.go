@@ -0,0 +1,26 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package files wraps a handful of filesystem operations the rest of the codebase needs, mainly
+// so callers get errors.Wrap-friendly error messages instead of raw os.PathError values.
+package files
+
+import "io/ioutil"
+
+// ReadFile reads the entire contents of path.
+func ReadFile(path string) ([]byte, error) {
+	return ioutil.ReadFile(path)
+}
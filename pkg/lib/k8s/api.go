@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	_apiNameLabel      = "apiName"
+	_apiEndpointLabel  = "cortex.dev/endpoint"
+	_apiNodeGroupsAnno = "cortex.dev/node-groups"
+)
+
+// ListDeployedAPIs reconstructs the userconfig.API for every api currently deployed in the
+// client's namespace, read back off the Deployments the operator created for them. It's enough
+// information for the capacity planner to bin-pack already-running apis alongside a pending
+// batch; it is not a substitute for the original config file.
+func (c *Client) ListDeployedAPIs() ([]userconfig.API, error) {
+	deployments, err := c.Clientset.AppsV1().Deployments(c.Namespace).List(context.Background(), kmetav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var apis []userconfig.API
+	for _, deployment := range deployments.Items {
+		name, ok := deployment.Labels[_apiNameLabel]
+		if !ok {
+			continue
+		}
+
+		endpoint := deployment.Annotations[_apiEndpointLabel]
+		maxReplicas := 1
+		if deployment.Spec.Replicas != nil {
+			maxReplicas = int(*deployment.Spec.Replicas)
+		}
+
+		var nodeGroups []string
+		if raw := deployment.Annotations[_apiNodeGroupsAnno]; raw != "" {
+			nodeGroups = strings.Split(raw, ",")
+		}
+
+		apis = append(apis, userconfig.API{
+			Name:        name,
+			Endpoint:    &endpoint,
+			Autoscaling: &userconfig.Autoscaling{MaxReplicas: maxReplicas},
+			NodeGroups:  nodeGroups,
+		})
+	}
+
+	return apis, nil
+}
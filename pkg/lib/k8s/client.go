@@ -0,0 +1,43 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package k8s is the operator's thin wrapper around client-go and the Istio dynamic client.
+package k8s
+
+import (
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Client bundles the typed and dynamic clients the operator needs to read and mutate cluster
+// state on behalf of a single namespace.
+type Client struct {
+	Clientset kubernetes.Interface
+	Dynamic   dynamic.Interface
+	Namespace string
+}
+
+func listOptionsFor(labelSelector map[string]string) kmetav1.ListOptions {
+	if len(labelSelector) == 0 {
+		return kmetav1.ListOptions{}
+	}
+	return kmetav1.ListOptions{LabelSelector: kmetav1.FormatLabelSelector(&kmetav1.LabelSelector{MatchLabels: labelSelector})}
+}
+
+func kgetOptions() kmetav1.GetOptions {
+	return kmetav1.GetOptions{}
+}
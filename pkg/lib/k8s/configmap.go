@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kcorev1 "k8s.io/api/core/v1"
+)
+
+// GetConfigMap fetches a ConfigMap by name, returning (nil, nil) if it doesn't exist yet.
+func (c *Client) GetConfigMap(name string) (*kcorev1.ConfigMap, error) {
+	cm, err := c.Clientset.CoreV1().ConfigMaps(c.Namespace).Get(context.Background(), name, kmetav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// UpdateConfigMapData merges data into the named ConfigMap's Data, creating the ConfigMap if it
+// doesn't exist yet.
+func (c *Client) UpdateConfigMapData(name string, data map[string]string) error {
+	configMaps := c.Clientset.CoreV1().ConfigMaps(c.Namespace)
+
+	cm, err := c.GetConfigMap(name)
+	if err != nil {
+		return err
+	}
+	if cm == nil {
+		_, err := configMaps.Create(context.Background(), &kcorev1.ConfigMap{
+			ObjectMeta: kmetav1.ObjectMeta{Name: name, Namespace: c.Namespace},
+			Data:       data,
+		}, kmetav1.CreateOptions{})
+		return err
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	for key, value := range data {
+		cm.Data[key] = value
+	}
+	_, err = configMaps.Update(context.Background(), cm, kmetav1.UpdateOptions{})
+	return err
+}
+
+// DeleteConfigMapKey removes a single key from the named ConfigMap's Data.
+func (c *Client) DeleteConfigMapKey(name string, key string) error {
+	cm, err := c.GetConfigMap(name)
+	if err != nil {
+		return err
+	}
+	if cm == nil || cm.Data == nil {
+		return nil
+	}
+	delete(cm.Data, key)
+	_, err = c.Clientset.CoreV1().ConfigMaps(c.Namespace).Update(context.Background(), cm, kmetav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// _deploymentReadyTimeout bounds how long WaitForDeploymentReady polls before giving up.
+const _deploymentReadyTimeout = 5 * time.Minute
+
+// GetDeployment fetches the named Deployment, returning (nil, nil) if it doesn't exist.
+func (c *Client) GetDeployment(name string) (*kappsv1.Deployment, error) {
+	deployment, err := c.Clientset.AppsV1().Deployments(c.Namespace).Get(context.Background(), name, kmetav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deployment, nil
+}
+
+// ApplyDeployment creates or replaces a Deployment with the given spec.
+func (c *Client) ApplyDeployment(deployment *kappsv1.Deployment) error {
+	deployments := c.Clientset.AppsV1().Deployments(c.Namespace)
+
+	existing, err := c.GetDeployment(deployment.Name)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, err := deployments.Create(context.Background(), deployment, kmetav1.CreateOptions{})
+		return err
+	}
+
+	deployment.ResourceVersion = existing.ResourceVersion
+	_, err = deployments.Update(context.Background(), deployment, kmetav1.UpdateOptions{})
+	return err
+}
+
+// DeleteDeployment deletes the named Deployment.
+func (c *Client) DeleteDeployment(name string) error {
+	return c.Clientset.AppsV1().Deployments(c.Namespace).Delete(context.Background(), name, kmetav1.DeleteOptions{})
+}
+
+// RenameDeployment recreates the named Deployment under newName and deletes the original. Istio
+// routing is driven by labels/subsets rather than the Deployment name, so this is safe to do
+// after traffic has already been shifted away from the old name.
+func (c *Client) RenameDeployment(name string, newName string) error {
+	deployment, err := c.GetDeployment(name)
+	if err != nil {
+		return err
+	}
+
+	renamed := deployment.DeepCopy()
+	renamed.Name = newName
+	renamed.ResourceVersion = ""
+	if err := c.ApplyDeployment(renamed); err != nil {
+		return err
+	}
+
+	return c.DeleteDeployment(name)
+}
+
+// RestartDeployment performs a rolling restart of every replica by bumping a restart annotation,
+// matching the plain `kubectl rollout restart` behavior.
+func (c *Client) RestartDeployment(name string) error {
+	deployment, err := c.GetDeployment(name)
+	if err != nil {
+		return err
+	}
+
+	if deployment.Spec.Template.ObjectMeta.Annotations == nil {
+		deployment.Spec.Template.ObjectMeta.Annotations = map[string]string{}
+	}
+	deployment.Spec.Template.ObjectMeta.Annotations["cortex.dev/restartedAt"] = time.Now().UTC().Format(time.RFC3339)
+
+	_, err = c.Clientset.AppsV1().Deployments(c.Namespace).Update(context.Background(), deployment, kmetav1.UpdateOptions{})
+	return err
+}
+
+// WaitForDeploymentReady polls the named Deployment until every desired replica is ready, or
+// returns an error after _deploymentReadyTimeout.
+func (c *Client) WaitForDeploymentReady(name string) error {
+	deadline := time.Now().Add(_deploymentReadyTimeout)
+
+	for time.Now().Before(deadline) {
+		deployment, err := c.GetDeployment(name)
+		if err != nil {
+			return err
+		}
+
+		desired := int32(1)
+		if deployment.Spec.Replicas != nil {
+			desired = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ReadyReplicas >= desired {
+			return nil
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+
+	return fmt.Errorf("deployment %s did not become ready within %s", name, _deploymentReadyTimeout)
+}
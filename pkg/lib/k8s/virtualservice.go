@@ -0,0 +1,151 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/lib/strset"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _virtualServiceGVR = kschema.GroupVersionResource{
+	Group:    "networking.istio.io",
+	Version:  "v1alpha3",
+	Resource: "virtualservices",
+}
+
+// ExtractVirtualServiceGateways reads the spec.gateways list off an Istio VirtualService.
+func ExtractVirtualServiceGateways(virtualService *kunstructured.Unstructured) (strset.Set, error) {
+	gateways, found, err := kunstructured.NestedStringSlice(virtualService.Object, "spec", "gateways")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return strset.New(), nil
+	}
+	return strset.New(gateways...), nil
+}
+
+// ExtractVirtualServiceEndpoints reads every spec.http[].match[].uri.prefix off an Istio
+// VirtualService, returning the set of endpoint prefixes it routes.
+func ExtractVirtualServiceEndpoints(virtualService *kunstructured.Unstructured) (strset.Set, error) {
+	endpoints := strset.New()
+
+	httpRoutes, found, err := kunstructured.NestedSlice(virtualService.Object, "spec", "http")
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return endpoints, nil
+	}
+
+	for _, route := range httpRoutes {
+		routeMap, ok := route.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches, found, err := kunstructured.NestedSlice(routeMap, "match")
+		if err != nil || !found {
+			continue
+		}
+		for _, match := range matches {
+			matchMap, ok := match.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			prefix, found, err := kunstructured.NestedString(matchMap, "uri", "prefix")
+			if err != nil || !found {
+				continue
+			}
+			endpoints.Add(prefix)
+		}
+	}
+
+	return endpoints, nil
+}
+
+// ListVirtualServices lists the VirtualServices in the client's namespace, optionally filtered
+// by label selector.
+func (c *Client) ListVirtualServices(labelSelector map[string]string) ([]kunstructured.Unstructured, error) {
+	list, err := c.Dynamic.Resource(_virtualServiceGVR).Namespace(c.Namespace).List(context.Background(), listOptionsFor(labelSelector))
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// ListVirtualServicesAllNamespaces lists VirtualServices across every namespace in the cluster,
+// used by the capacity planner so cross-namespace endpoint collisions aren't missed.
+func (c *Client) ListVirtualServicesAllNamespaces(labelSelector map[string]string) ([]kunstructured.Unstructured, error) {
+	list, err := c.Dynamic.Resource(_virtualServiceGVR).Namespace("").List(context.Background(), listOptionsFor(labelSelector))
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// GetVirtualService fetches the VirtualService named apiName in the client's namespace.
+func (c *Client) GetVirtualService(apiName string) (*kunstructured.Unstructured, error) {
+	return c.Dynamic.Resource(_virtualServiceGVR).Namespace(c.Namespace).Get(context.Background(), apiName, kgetOptions())
+}
+
+// UpdateVirtualService persists virtualService's current in-memory Object back to the cluster,
+// e.g. after SetVirtualServiceRouteWeights has rewritten its route weights.
+func (c *Client) UpdateVirtualService(virtualService *kunstructured.Unstructured) (*kunstructured.Unstructured, error) {
+	return c.Dynamic.Resource(_virtualServiceGVR).Namespace(c.Namespace).Update(context.Background(), virtualService, kmetav1.UpdateOptions{})
+}
+
+// SetVirtualServiceRouteWeights rewrites every spec.http[].route[].weight on virtualService so
+// that each destination subset named in weights gets its corresponding percentage of traffic.
+func SetVirtualServiceRouteWeights(virtualService *kunstructured.Unstructured, weights map[string]int) error {
+	httpRoutes, found, err := kunstructured.NestedSlice(virtualService.Object, "spec", "http")
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("virtualservice %s has no http routes", virtualService.GetName())
+	}
+
+	for _, route := range httpRoutes {
+		routeMap, ok := route.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		destinations, found, err := kunstructured.NestedSlice(routeMap, "route")
+		if err != nil || !found {
+			continue
+		}
+		for _, destination := range destinations {
+			destMap, ok := destination.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			subset, _, _ := kunstructured.NestedString(destMap, "destination", "subset")
+			if weight, ok := weights[subset]; ok {
+				destMap["weight"] = int64(weight)
+			}
+		}
+		// NestedSlice returns a deep copy, so the updated destinations must be stored back.
+		routeMap["route"] = destinations
+	}
+
+	return kunstructured.SetNestedSlice(virtualService.Object, httpRoutes, "spec", "http")
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import (
+	"testing"
+
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newTestVirtualService(gateways []string, prefixes []string, routeWeights map[string]int) *kunstructured.Unstructured {
+	var routes []interface{}
+	for subset, weight := range routeWeights {
+		routes = append(routes, map[string]interface{}{
+			"destination": map[string]interface{}{"subset": subset},
+			"weight":      int64(weight),
+		})
+	}
+
+	var matches []interface{}
+	for _, prefix := range prefixes {
+		matches = append(matches, map[string]interface{}{
+			"uri": map[string]interface{}{"prefix": prefix},
+		})
+	}
+
+	gatewaysIface := make([]interface{}, len(gateways))
+	for i, gateway := range gateways {
+		gatewaysIface[i] = gateway
+	}
+
+	return &kunstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"gateways": gatewaysIface,
+				"http": []interface{}{
+					map[string]interface{}{
+						"match": matches,
+						"route": routes,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestExtractVirtualServiceGateways(t *testing.T) {
+	vs := newTestVirtualService([]string{"apis-gateway", "other-gateway"}, []string{"/my-api"}, nil)
+
+	gateways, err := ExtractVirtualServiceGateways(vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !gateways.Has("apis-gateway") || !gateways.Has("other-gateway") {
+		t.Fatalf("expected both gateways to be extracted, got %v", gateways.Slice())
+	}
+}
+
+func TestExtractVirtualServiceGatewaysMissing(t *testing.T) {
+	vs := &kunstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+
+	gateways, err := ExtractVirtualServiceGateways(vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gateways) != 0 {
+		t.Fatalf("expected no gateways, got %v", gateways.Slice())
+	}
+}
+
+func TestExtractVirtualServiceEndpoints(t *testing.T) {
+	vs := newTestVirtualService([]string{"apis-gateway"}, []string{"/my-api", "/my-api/v2"}, nil)
+
+	endpoints, err := ExtractVirtualServiceEndpoints(vs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !endpoints.Has("/my-api") || !endpoints.Has("/my-api/v2") {
+		t.Fatalf("expected both endpoint prefixes to be extracted, got %v", endpoints.Slice())
+	}
+}
+
+func TestSetVirtualServiceRouteWeights(t *testing.T) {
+	vs := newTestVirtualService([]string{"apis-gateway"}, []string{"/my-api"}, map[string]int{
+		"my-api":        100,
+		"my-api-shadow": 0,
+	})
+
+	err := SetVirtualServiceRouteWeights(vs, map[string]int{
+		"my-api":        90,
+		"my-api-shadow": 10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	httpRoutes, _, _ := kunstructured.NestedSlice(vs.Object, "spec", "http")
+	routeMap := httpRoutes[0].(map[string]interface{})
+	destinations, _, _ := kunstructured.NestedSlice(routeMap, "route")
+
+	weights := map[string]int64{}
+	for _, destination := range destinations {
+		destMap := destination.(map[string]interface{})
+		subset, _, _ := kunstructured.NestedString(destMap, "destination", "subset")
+		weight, _, _ := kunstructured.NestedInt64(destMap, "weight")
+		weights[subset] = weight
+	}
+
+	if weights["my-api"] != 90 {
+		t.Errorf("expected my-api weight to be 90, got %d", weights["my-api"])
+	}
+	if weights["my-api-shadow"] != 10 {
+		t.Errorf("expected my-api-shadow weight to be 10, got %d", weights["my-api-shadow"])
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates Rego policies against a userconfig.API. It depends only on
+// pkg/types/userconfig, not on the operator's k8s/Prometheus clients, so both the CLI and the
+// operator can run the same OPA check without the CLI pulling in server-only packages.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// Validator is implemented by anything that can reject an API as part of a validation pass. api
+// is the one currently being checked; apis is the full pending batch, so a validator can reason
+// about collisions within the batch as well as against the one API.
+type Validator interface {
+	Validate(api *userconfig.API, apis []userconfig.API) error
+}
+
+// OPAValidator evaluates a Rego policy (e.g. required labels, allowed base images, GPU quotas per
+// namespace, endpoint naming conventions) against the parsed userconfig.API. The policy is
+// expected to set data.cortex.deny to a (possibly empty) array of violation strings.
+type OPAValidator struct {
+	PolicyFile string
+	query      rego.PreparedEvalQuery
+}
+
+// NewOPAValidator loads and compiles the Rego module at policyFile. It's used both by the
+// operator, to enforce cluster-registered policies server-side, and by `cortex validate
+// --policy-file`, to run a one-off policy locally before submission.
+func NewOPAValidator(policyFile string) (*OPAValidator, error) {
+	policy, err := files.ReadFile(policyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, policyFile)
+	}
+
+	query, err := rego.New(
+		rego.Query("data.cortex.deny"),
+		rego.Module(policyFile, string(policy)),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, errors.Wrap(err, policyFile)
+	}
+
+	return &OPAValidator{PolicyFile: policyFile, query: query}, nil
+}
+
+func (v *OPAValidator) Validate(api *userconfig.API, apis []userconfig.API) error {
+	input, err := apiToRegoInput(api)
+	if err != nil {
+		return errors.Wrap(err, v.PolicyFile)
+	}
+
+	results, err := v.query.Eval(context.Background(), rego.EvalInput(input))
+	if err != nil {
+		return errors.Wrap(err, v.PolicyFile)
+	}
+
+	var violations []string
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			if denies, ok := expression.Value.([]interface{}); ok {
+				for _, deny := range denies {
+					if msg, ok := deny.(string); ok {
+						violations = append(violations, msg)
+					}
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return ErrorPolicyViolation(v.PolicyFile, violations)
+	}
+
+	return nil
+}
+
+func apiToRegoInput(api *userconfig.API) (map[string]interface{}, error) {
+	raw, err := json.Marshal(api)
+	if err != nil {
+		return nil, err
+	}
+
+	var input map[string]interface{}
+	if err := json.Unmarshal(raw, &input); err != nil {
+		return nil, err
+	}
+
+	return input, nil
+}
+
+func ErrorPolicyViolation(policyFile string, violations []string) error {
+	return fmt.Errorf("opa policy %s denied the request: %s", policyFile, strings.Join(violations, "; "))
+}
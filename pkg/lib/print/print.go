@@ -0,0 +1,60 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package print renders CLI command output.
+package print
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+// BoldFirstLine prints message as a command's headline result.
+func BoldFirstLine(message string) {
+	fmt.Println(message)
+}
+
+// Progress renders one line of a live progress bar for a streamed refresh, overwriting the
+// previous line.
+func Progress(stage string, percentComplete int, message string) {
+	fmt.Printf("\r%s: %d%% - %s", stage, percentComplete, message)
+	if percentComplete == 100 {
+		fmt.Println()
+	}
+}
+
+// CapacityPlan renders the capacity planner's report for `cortex plan`.
+func CapacityPlan(plan *schema.Plan) {
+	if len(plan.AdditionalNodesByInstanceType) == 0 {
+		fmt.Println("no additional nodes are required")
+	} else {
+		fmt.Println("additional nodes required:")
+		for instanceType, count := range plan.AdditionalNodesByInstanceType {
+			fmt.Printf("  %s: %d\n", instanceType, count)
+		}
+	}
+
+	for _, api := range plan.UnschedulableAPIs {
+		fmt.Printf("warning: %s cannot be scheduled on any available instance type\n", api)
+	}
+
+	for _, collision := range plan.EndpointCollisions {
+		fmt.Printf("warning: endpoint %s on api %s collides with an existing api on gateway %s\n", collision.Endpoint, collision.APIName, collision.Gateway)
+	}
+
+	fmt.Printf("estimated hourly cost delta: $%.2f\n", plan.EstimatedHourlyCostDelta)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strset
+
+// Set is a set of strings backed by a map.
+type Set map[string]struct{}
+
+// New returns a Set containing items.
+func New(items ...string) Set {
+	s := make(Set, len(items))
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+	return s
+}
+
+// Add inserts item into the set.
+func (s Set) Add(item string) {
+	s[item] = struct{}{}
+}
+
+// Has returns whether item is in the set.
+func (s Set) Has(item string) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// HasAny returns whether any of items is in the set.
+func (s Set) HasAny(items ...string) bool {
+	for _, item := range items {
+		if s.Has(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Slice returns the set's members in no particular order.
+func (s Set) Slice() []string {
+	out := make([]string, 0, len(s))
+	for item := range s {
+		out = append(out, item)
+	}
+	return out
+}
@@ -0,0 +1,25 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package telemetry records anonymous CLI usage events. Reporting is best-effort and must never
+// affect a command's behavior or exit status, so Event never returns an error.
+package telemetry
+
+// Event records that a CLI command ran, along with any properties worth aggregating on (e.g. the
+// refresh strategy chosen). Properties are optional.
+func Event(name string, properties ...map[string]interface{}) {
+	// telemetry reporting isn't wired up in this build; calls are no-ops.
+}
@@ -0,0 +1,30 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config holds the operator process's singletons for talking to the cluster it manages:
+// the live cluster config and the k8s/Prometheus clients initialized at startup.
+package config
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+)
+
+var (
+	Cluster    *clusterconfig.InternalConfig
+	K8s        *k8s.Client
+	Prometheus *PrometheusClient
+)
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// _defaultMaxErrorRate and _defaultMaxP95LatencySecs back the SLO check when the cluster config
+// doesn't set clusterconfig.SLOConfig (e.g. an older config predating this field).
+const (
+	_defaultMaxErrorRate      = 0.01
+	_defaultMaxP95LatencySecs = 0.5
+)
+
+// PrometheusClient evaluates SLO expressions (error rate, p95 latency, ...) against the
+// cluster's Prometheus so a canary/blue-green bake window can be aborted on regression.
+type PrometheusClient struct {
+	API promv1.API
+}
+
+// NewPrometheusClient builds a PrometheusClient pointed at address (e.g.
+// "http://prometheus.istio-system:9090").
+func NewPrometheusClient(address string) (*PrometheusClient, error) {
+	client, err := promapi.NewClient(promapi.Config{Address: address})
+	if err != nil {
+		return nil, err
+	}
+	return &PrometheusClient{API: promv1.NewAPI(client)}, nil
+}
+
+// CheckSLO evaluates the api's SLO expression - error rate and p95 latency, thresholds taken
+// from the cluster's configured clusterconfig.SLOConfig - and returns whether both are
+// currently satisfied.
+func (p *PrometheusClient) CheckSLO(api string) (bool, error) {
+	expr := sloExpressionForAPI(api, Cluster.SLO)
+
+	result, _, err := p.API.Query(context.Background(), expr, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	vector, ok := result.(model.Vector)
+	if !ok || len(vector) == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// sloExpressionForAPI builds a PromQL expression that's only non-empty when both the error rate
+// and the p95 latency are within slo's configured thresholds, falling back to the defaults this
+// package shipped with originally when the cluster config leaves SLOConfig unset.
+func sloExpressionForAPI(api string, slo clusterconfig.SLOConfig) string {
+	maxErrorRate := slo.MaxErrorRate
+	if maxErrorRate == 0 {
+		maxErrorRate = _defaultMaxErrorRate
+	}
+	maxP95LatencySecs := slo.MaxP95LatencySecs
+	if maxP95LatencySecs == 0 {
+		maxP95LatencySecs = _defaultMaxP95LatencySecs
+	}
+
+	errorRateExpr := fmt.Sprintf(
+		`sum(rate(istio_requests_total{destination_app=%q,response_code=~"5.."}[5m])) / sum(rate(istio_requests_total{destination_app=%q}[5m])) < %v`,
+		api, api, maxErrorRate,
+	)
+	p95LatencyExpr := fmt.Sprintf(
+		`histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{destination_app=%q}[5m])) by (le)) < %v`,
+		api, maxP95LatencySecs*1000,
+	)
+
+	return "(" + errorRateExpr + ") and (" + p95LatencyExpr + ")"
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Resources reserved on every node for cortex's own daemonsets and the nvidia device plugin,
+// subtracted from the node's advertised capacity before checking whether an api's compute
+// request fits.
+var (
+	_cortexMemReserve = kresource.MustParse("200Mi")
+	_cortexCPUReserve = kresource.MustParse("100m")
+	_nvidiaMemReserve = kresource.MustParse("50Mi")
+	_nvidiaCPUReserve = kresource.MustParse("50m")
+)
+
+const _memoryCapacityConfigMapName = "cortex-memory-capacity"
+
+// updateMemoryCapacityConfigMap returns the smallest available node memory across the cluster,
+// caching it in a ConfigMap the way gpu_share.go caches per-device GPU memory.
+func updateMemoryCapacityConfigMap() (*kresource.Quantity, error) {
+	nodes, err := config.K8s.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	var minMem *kresource.Quantity
+	for _, node := range nodes {
+		mem := node.Status.Capacity.Memory()
+		if minMem == nil || mem.Cmp(*minMem) < 0 {
+			minMem = mem
+		}
+	}
+	if minMem == nil {
+		zero := kresource.MustParse("0")
+		minMem = &zero
+	}
+
+	_ = config.K8s.UpdateConfigMapData(_memoryCapacityConfigMapName, map[string]string{"min_node_memory": minMem.String()})
+
+	return minMem, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+)
+
+func ErrorNoAvailableNodeComputeLimit(resource string, requested string, max string) error {
+	return fmt.Errorf("no instance type in the cluster has enough available %s (requested %s, max available %s)", resource, requested, max)
+}
+
+func ErrorNoAvailableGPUMemory(requested string) error {
+	return fmt.Errorf("no gpu device in the cluster has %s of free memory available to share", requested)
+}
+
+func ErrorComputeGPUAndGPUMemMutuallyExclusive() error {
+	return fmt.Errorf("compute.gpu and compute.gpu_mem cannot both be set; request a whole gpu or a memory-bounded share, not both")
+}
+
+func ErrorInvalidRefreshStrategy(strategy string) error {
+	return fmt.Errorf("%s: not a supported refresh strategy (expected rolling, canary, or blue-green)", strategy)
+}
+
+func ErrorRefreshMissingImage(strategy string) error {
+	return fmt.Errorf("--image is required for the %s refresh strategy: there is no new version to shift traffic to otherwise", strategy)
+}
+
+func ErrorValidatingWebhookRejected(endpoint string, reason string) error {
+	return fmt.Errorf("validating webhook %s rejected the request: %s", endpoint, reason)
+}
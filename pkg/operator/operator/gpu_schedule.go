@@ -0,0 +1,211 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kappsv1 "k8s.io/api/apps/v1"
+	kcorev1 "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kwatch "k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// _gpuDeviceIndexAnnotation records which physical device a pod's shared GPU slice lives on,
+	// so the device can be freed again when the pod is deleted.
+	_gpuDeviceIndexAnnotation = "cortex.dev/gpu-device-index"
+	_gpuDeviceNodeAnnotation  = "cortex.dev/gpu-device-node"
+	_gpuDeviceMemAnnotation   = "cortex.dev/gpu-device-mem"
+	_gpuShareLabel            = "cortex.dev/gpu-share"
+	_gpuWholeLabel            = "cortex.dev/gpu-whole"
+	// _gpuReservedLabel is set alongside _gpuShareLabel or _gpuWholeLabel on every pod that holds
+	// a device reservation, so WatchGPUSharePods can watch both kinds with one label selector.
+	_gpuReservedLabel = "cortex.dev/gpu-reserved"
+
+	_envNvidiaVisibleDevices = "NVIDIA_VISIBLE_DEVICES"
+	_envGPUMemLimit          = "CORTEX_GPU_MEM_LIMIT_MB"
+)
+
+// PrepareDeploymentForGPUShare reserves a shared GPU slice for api and patches deployment so its
+// pods land on the assigned device: a node selector ties the pod to the node that has the slice,
+// a pod annotation records the assignment for later release, and NVIDIA_VISIBLE_DEVICES plus a
+// memory-limit env var make every container in the pod time-share that one device. It is a no-op
+// when api doesn't request a GPU share.
+func PrepareDeploymentForGPUShare(deployment *kappsv1.Deployment, api *userconfig.API) error {
+	if api.Compute == nil || api.Compute.GPUMem == nil {
+		return nil
+	}
+
+	nodeName, deviceIndex, err := assignGPUDeviceAnywhere(api.Compute.GPUMem.Quantity)
+	if err != nil {
+		return errors.Wrap(err, api.Identify(), userconfig.ComputeKey)
+	}
+
+	template := &deployment.Spec.Template
+	if template.Spec.NodeSelector == nil {
+		template.Spec.NodeSelector = map[string]string{}
+	}
+	template.Spec.NodeSelector["kubernetes.io/hostname"] = nodeName
+
+	if template.ObjectMeta.Labels == nil {
+		template.ObjectMeta.Labels = map[string]string{}
+	}
+	template.ObjectMeta.Labels[_gpuShareLabel] = "true"
+	template.ObjectMeta.Labels[_gpuReservedLabel] = "true"
+
+	if template.ObjectMeta.Annotations == nil {
+		template.ObjectMeta.Annotations = map[string]string{}
+	}
+	template.ObjectMeta.Annotations[_gpuDeviceNodeAnnotation] = nodeName
+	template.ObjectMeta.Annotations[_gpuDeviceIndexAnnotation] = strconv.Itoa(deviceIndex)
+	template.ObjectMeta.Annotations[_gpuDeviceMemAnnotation] = api.Compute.GPUMem.String()
+
+	memLimitMB := strconv.FormatInt(api.Compute.GPUMem.Value()/(1024*1024), 10)
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Env = append(template.Spec.Containers[i].Env,
+			kcorev1.EnvVar{Name: _envNvidiaVisibleDevices, Value: strconv.Itoa(deviceIndex)},
+			kcorev1.EnvVar{Name: _envGPUMemLimit, Value: memLimitMB},
+		)
+	}
+
+	return nil
+}
+
+// PrepareDeploymentForWholeGPU reserves a whole (non-shared) GPU device for api and patches
+// deployment so its pods land on it: a node selector ties the pod to the node that has the
+// device, and NVIDIA_VISIBLE_DEVICES pins it to that one device index. Without this, a whole-GPU
+// pod is scheduled purely by Kubernetes' own device plugin with no visibility into the
+// cortex-gpu-memory-capacity ConfigMap, so it could land on a device a memory-share pod is
+// already using; reserving through assignWholeGPUDeviceAnywhere first closes that gap. It is a
+// no-op when api doesn't request a whole GPU.
+func PrepareDeploymentForWholeGPU(deployment *kappsv1.Deployment, api *userconfig.API) error {
+	if api.Compute == nil || api.Compute.GPU == 0 {
+		return nil
+	}
+
+	nodeName, deviceIndex, err := assignWholeGPUDeviceAnywhere()
+	if err != nil {
+		return errors.Wrap(err, api.Identify(), userconfig.ComputeKey)
+	}
+
+	template := &deployment.Spec.Template
+	if template.Spec.NodeSelector == nil {
+		template.Spec.NodeSelector = map[string]string{}
+	}
+	template.Spec.NodeSelector["kubernetes.io/hostname"] = nodeName
+
+	if template.ObjectMeta.Labels == nil {
+		template.ObjectMeta.Labels = map[string]string{}
+	}
+	template.ObjectMeta.Labels[_gpuWholeLabel] = "true"
+	template.ObjectMeta.Labels[_gpuReservedLabel] = "true"
+
+	if template.ObjectMeta.Annotations == nil {
+		template.ObjectMeta.Annotations = map[string]string{}
+	}
+	template.ObjectMeta.Annotations[_gpuDeviceNodeAnnotation] = nodeName
+	template.ObjectMeta.Annotations[_gpuDeviceIndexAnnotation] = strconv.Itoa(deviceIndex)
+
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Env = append(template.Spec.Containers[i].Env,
+			kcorev1.EnvVar{Name: _envNvidiaVisibleDevices, Value: strconv.Itoa(deviceIndex)},
+		)
+	}
+
+	return nil
+}
+
+// assignGPUDeviceAnywhere is like assignGPUDevice, but also picks which node to assign on,
+// trying every shareable device cluster-wide until one has enough free memory.
+func assignGPUDeviceAnywhere(gpuMem kresource.Quantity) (string, int, error) {
+	devices, err := listGPUDevices()
+	if err != nil {
+		return "", -1, err
+	}
+
+	for _, device := range devices {
+		index, err := assignGPUDevice(device.nodeName, gpuMem)
+		if err != nil {
+			continue
+		}
+		return device.nodeName, index, nil
+	}
+
+	return "", -1, ErrorNoAvailableGPUMemory(gpuMem.String())
+}
+
+// ReleaseGPUShareForPod frees the GPU device recorded on pod's annotations, if any - whether it
+// was a memory-shared slice or a whole device reserved by PrepareDeploymentForWholeGPU.
+func ReleaseGPUShareForPod(pod *kcorev1.Pod) error {
+	nodeName, ok := pod.Annotations[_gpuDeviceNodeAnnotation]
+	if !ok {
+		return nil
+	}
+
+	index, err := strconv.Atoi(pod.Annotations[_gpuDeviceIndexAnnotation])
+	if err != nil {
+		return errors.Wrap(err, pod.Name, _gpuDeviceIndexAnnotation)
+	}
+
+	memStr, isShared := pod.Annotations[_gpuDeviceMemAnnotation]
+	if !isShared {
+		return releaseWholeGPUDevice(nodeName, index)
+	}
+
+	gpuMem, err := kresource.ParseQuantity(memStr)
+	if err != nil {
+		return errors.Wrap(err, pod.Name, _gpuDeviceMemAnnotation)
+	}
+
+	return releaseGPUDevice(nodeName, index, gpuMem)
+}
+
+// WatchGPUSharePods watches every gpu-share or gpu-whole pod for deletion and releases its
+// device back to the pool as soon as it's torn down. It runs until ctx is cancelled or the watch
+// channel closes.
+func WatchGPUSharePods(ctx context.Context) error {
+	pods := config.K8s.Clientset.CoreV1().Pods(config.K8s.Namespace)
+
+	watcher, err := pods.Watch(ctx, kmetav1.ListOptions{LabelSelector: _gpuReservedLabel + "=true"})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return nil
+			}
+			if event.Type != kwatch.Deleted {
+				continue
+			}
+			if pod, ok := event.Object.(*kcorev1.Pod); ok {
+				_ = ReleaseGPUShareForPod(pod)
+			}
+		}
+	}
+}
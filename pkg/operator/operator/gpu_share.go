@@ -0,0 +1,287 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kretry "k8s.io/client-go/util/retry"
+)
+
+// _gpuMemoryTotalAnnotation is set by the device plugin (or an equivalent Cortex-owned
+// annotation) on every node that exposes a shareable GPU, e.g. "volcano.sh/gpu-memory-total".
+const _gpuMemoryTotalAnnotation = "volcano.sh/gpu-memory-total"
+
+// _gpuMemoryConfigMapName tracks, per node and device index, how much GPU memory has already
+// been handed out to shared-GPU pods. It is updated read-modify-write, retrying on update
+// conflicts.
+const _gpuMemoryConfigMapName = "cortex-gpu-memory-capacity"
+
+// gpuDeviceUsageKey is the ConfigMap key used to track used memory for a single GPU device on a
+// single node, e.g. "ip-10-0-1-23.ec2.internal/0".
+func gpuDeviceUsageKey(nodeName string, deviceIndex int) string {
+	return fmt.Sprintf("%s/%d", nodeName, deviceIndex)
+}
+
+// validateGPUShare rejects the request unless at least one GPU device, on any instance in the
+// cluster, has enough free memory left to satisfy it. A device that is already running a
+// whole-GPU (non-shared) request is never considered for sharing.
+func validateGPUShare(gpuMem *kresource.Quantity) error {
+	devices, err := listGPUDevices()
+	if err != nil {
+		return err
+	}
+
+	usage, err := readGPUMemoryConfigMap()
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		if usage.wholeGPU[gpuDeviceUsageKey(device.nodeName, device.index)] {
+			continue
+		}
+
+		used := usage.usedMem[gpuDeviceUsageKey(device.nodeName, device.index)]
+		free := device.totalMem.DeepCopy()
+		free.Sub(used)
+
+		if free.Cmp(*gpuMem) >= 0 {
+			return nil
+		}
+	}
+
+	return ErrorNoAvailableGPUMemory(gpuMem.String())
+}
+
+type gpuDevice struct {
+	nodeName string
+	index    int
+	totalMem kresource.Quantity
+}
+
+// listGPUDevices enumerates every shareable GPU device across the cluster by reading the
+// per-node memory-total annotation and fanning it out per device index reported on the node.
+func listGPUDevices() ([]gpuDevice, error) {
+	nodes, err := config.K8s.ListNodesByLabel("nvidia.com/gpu", "true")
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []gpuDevice
+	for _, node := range nodes {
+		totalStr, ok := node.Annotations[_gpuMemoryTotalAnnotation]
+		if !ok {
+			continue
+		}
+
+		total, err := kresource.ParseQuantity(totalStr)
+		if err != nil {
+			return nil, errors.Wrap(err, "node", node.Name, _gpuMemoryTotalAnnotation)
+		}
+
+		gpuCount, _ := strconv.Atoi(node.Labels["nvidia.com/gpu.count"])
+		if gpuCount == 0 {
+			gpuCount = 1
+		}
+
+		for i := 0; i < gpuCount; i++ {
+			devices = append(devices, gpuDevice{nodeName: node.Name, index: i, totalMem: total})
+		}
+	}
+
+	return devices, nil
+}
+
+type gpuMemoryUsage struct {
+	usedMem  map[string]kresource.Quantity
+	wholeGPU map[string]bool
+}
+
+func readGPUMemoryConfigMap() (*gpuMemoryUsage, error) {
+	usage := &gpuMemoryUsage{
+		usedMem:  map[string]kresource.Quantity{},
+		wholeGPU: map[string]bool{},
+	}
+
+	cm, err := config.K8s.GetConfigMap(_gpuMemoryConfigMapName)
+	if err != nil {
+		return nil, err
+	}
+	if cm == nil {
+		return usage, nil
+	}
+
+	for key, val := range cm.Data {
+		if val == "whole" {
+			usage.wholeGPU[key] = true
+			continue
+		}
+		qty, err := kresource.ParseQuantity(val)
+		if err != nil {
+			continue
+		}
+		usage.usedMem[key] = qty
+	}
+
+	return usage, nil
+}
+
+// assignGPUDevice picks the first device on the target node with enough free memory for
+// gpuMem, reserves it in the shared-GPU ConfigMap, and returns its index so the pod can be
+// patched with NVIDIA_VISIBLE_DEVICES. It never assigns a device that is already running a
+// whole-GPU workload.
+func assignGPUDevice(nodeName string, gpuMem kresource.Quantity) (int, error) {
+	var assigned = -1
+
+	err := kretry.RetryOnConflict(kretry.DefaultRetry, func() error {
+		usage, err := readGPUMemoryConfigMap()
+		if err != nil {
+			return err
+		}
+
+		devices, err := listGPUDevices()
+		if err != nil {
+			return err
+		}
+
+		for _, device := range devices {
+			if device.nodeName != nodeName {
+				continue
+			}
+			key := gpuDeviceUsageKey(device.nodeName, device.index)
+			if usage.wholeGPU[key] {
+				continue
+			}
+
+			free := device.totalMem.DeepCopy()
+			used := usage.usedMem[key]
+			free.Sub(used)
+			if free.Cmp(gpuMem) < 0 {
+				continue
+			}
+
+			used.Add(gpuMem)
+			assigned = device.index
+			return config.K8s.UpdateConfigMapData(_gpuMemoryConfigMapName, map[string]string{key: used.String()})
+		}
+
+		return ErrorNoAvailableGPUMemory(gpuMem.String())
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	return assigned, nil
+}
+
+// assignWholeGPUDevice reserves a completely unused device on nodeName for a whole-GPU
+// (non-shared) pod, marking it "whole" in the same ConfigMap assignGPUDevice reads. This is what
+// stops a memory-share pod from ever landing on a device a whole-GPU pod already owns, and vice
+// versa: assignGPUDevice already skips any key marked "whole", and this refuses any device that
+// already has a non-zero usedMem entry.
+func assignWholeGPUDevice(nodeName string) (int, error) {
+	var assigned = -1
+
+	err := kretry.RetryOnConflict(kretry.DefaultRetry, func() error {
+		usage, err := readGPUMemoryConfigMap()
+		if err != nil {
+			return err
+		}
+
+		devices, err := listGPUDevices()
+		if err != nil {
+			return err
+		}
+
+		for _, device := range devices {
+			if device.nodeName != nodeName {
+				continue
+			}
+			key := gpuDeviceUsageKey(device.nodeName, device.index)
+			if usage.wholeGPU[key] {
+				continue
+			}
+			if used, ok := usage.usedMem[key]; ok && used.Sign() > 0 {
+				continue
+			}
+
+			assigned = device.index
+			return config.K8s.UpdateConfigMapData(_gpuMemoryConfigMapName, map[string]string{key: "whole"})
+		}
+
+		return ErrorNoAvailableGPUMemory("a whole device")
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	return assigned, nil
+}
+
+// assignWholeGPUDeviceAnywhere is like assignWholeGPUDevice, but also picks which node to assign
+// on, trying every device cluster-wide until one is completely free.
+func assignWholeGPUDeviceAnywhere() (string, int, error) {
+	devices, err := listGPUDevices()
+	if err != nil {
+		return "", -1, err
+	}
+
+	for _, device := range devices {
+		index, err := assignWholeGPUDevice(device.nodeName)
+		if err != nil {
+			continue
+		}
+		return device.nodeName, index, nil
+	}
+
+	return "", -1, ErrorNoAvailableGPUMemory("a whole device")
+}
+
+// releaseWholeGPUDevice clears the "whole" marker left by assignWholeGPUDevice, retrying on
+// update conflicts since multiple pods can be deleted concurrently.
+func releaseWholeGPUDevice(nodeName string, deviceIndex int) error {
+	key := gpuDeviceUsageKey(nodeName, deviceIndex)
+
+	return kretry.RetryOnConflict(kretry.DefaultRetry, func() error {
+		return config.K8s.DeleteConfigMapKey(_gpuMemoryConfigMapName, key)
+	})
+}
+
+// releaseGPUDevice decrements the shared-GPU ConfigMap counter for a deleted pod's device,
+// retrying on update conflicts since multiple pods can be deleted concurrently.
+func releaseGPUDevice(nodeName string, deviceIndex int, gpuMem kresource.Quantity) error {
+	key := gpuDeviceUsageKey(nodeName, deviceIndex)
+
+	return kretry.RetryOnConflict(kretry.DefaultRetry, func() error {
+		usage, err := readGPUMemoryConfigMap()
+		if err != nil {
+			return err
+		}
+
+		used := usage.usedMem[key]
+		used.Sub(gpuMem)
+		if used.Sign() <= 0 {
+			return config.K8s.DeleteConfigMapKey(_gpuMemoryConfigMapName, key)
+		}
+		return config.K8s.UpdateConfigMapData(_gpuMemoryConfigMapName, map[string]string{key: used.String()})
+	})
+}
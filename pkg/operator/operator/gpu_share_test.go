@@ -0,0 +1,121 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	kcorev1 "k8s.io/api/core/v1"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newGPUNode registers a node with two shareable GPU devices, matching what listGPUDevices
+// expects to find (the nvidia.com/gpu=true label plus the memory-total annotation).
+func newGPUNode(name string, totalMem string) *kcorev1.Node {
+	return &kcorev1.Node{
+		ObjectMeta: kmetav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"nvidia.com/gpu": "true", "nvidia.com/gpu.count": "2"},
+			Annotations: map[string]string{_gpuMemoryTotalAnnotation: totalMem},
+		},
+	}
+}
+
+func TestAssignAndReleaseGPUDevice(t *testing.T) {
+	node := newGPUNode("node-a", "8Gi")
+	config.K8s = &k8s.Client{Clientset: fake.NewSimpleClientset(node), Namespace: "default"}
+
+	gpuMem := kresource.MustParse("4Gi")
+
+	index, err := assignGPUDevice("node-a", gpuMem)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if index != 0 {
+		t.Fatalf("expected the first device (index 0) to be assigned, got %d", index)
+	}
+
+	// The device only has 8Gi total; a second 4Gi share should still fit on device 0 before
+	// spilling onto device 1.
+	secondIndex, err := assignGPUDevice("node-a", gpuMem)
+	if err != nil {
+		t.Fatalf("unexpected error assigning a second share: %v", err)
+	}
+	if secondIndex != 0 {
+		t.Fatalf("expected the second share to fill the remaining 4Gi of device 0, got %d", secondIndex)
+	}
+
+	if err := releaseGPUDevice("node-a", 0, gpuMem); err != nil {
+		t.Fatalf("unexpected error releasing device: %v", err)
+	}
+
+	// Releasing should free device 0 back up for another 4Gi share.
+	thirdIndex, err := assignGPUDevice("node-a", gpuMem)
+	if err != nil {
+		t.Fatalf("unexpected error reassigning released device: %v", err)
+	}
+	if thirdIndex != 0 {
+		t.Fatalf("expected the released device 0 to be reused, got %d", thirdIndex)
+	}
+}
+
+func TestAssignGPUDeviceNoCapacity(t *testing.T) {
+	node := newGPUNode("node-a", "2Gi")
+	config.K8s = &k8s.Client{Clientset: fake.NewSimpleClientset(node), Namespace: "default"}
+
+	gpuMem := kresource.MustParse("4Gi")
+	if _, err := assignGPUDevice("node-a", gpuMem); err == nil {
+		t.Fatal("expected an error when no device has enough free memory")
+	}
+}
+
+func TestWholeGPUAndSharedGPUNeverCollide(t *testing.T) {
+	node := newGPUNode("node-a", "8Gi")
+	config.K8s = &k8s.Client{Clientset: fake.NewSimpleClientset(node), Namespace: "default"}
+
+	wholeIndex, err := assignWholeGPUDevice("node-a")
+	if err != nil {
+		t.Fatalf("unexpected error reserving a whole device: %v", err)
+	}
+
+	// A shared-GPU request should now skip the device reserved whole and land on the other one.
+	sharedIndex, err := assignGPUDevice("node-a", kresource.MustParse("4Gi"))
+	if err != nil {
+		t.Fatalf("unexpected error assigning a shared device: %v", err)
+	}
+	if sharedIndex == wholeIndex {
+		t.Fatalf("expected the shared device (%d) to differ from the whole-GPU device (%d)", sharedIndex, wholeIndex)
+	}
+
+	// A second whole-GPU reservation must likewise skip the device already in shared use; with
+	// the other device reserved whole, no device is left for it.
+	secondWholeIndex, err := assignWholeGPUDevice("node-a")
+	if err == nil {
+		t.Fatalf("expected reserving a second whole device to fail, got device %d (shared device is %d)", secondWholeIndex, sharedIndex)
+	}
+	if secondWholeIndex != -1 {
+		t.Fatalf("expected no device index for the failed whole-GPU reservation, got %d", secondWholeIndex)
+	}
+
+	if err := releaseWholeGPUDevice("node-a", wholeIndex); err != nil {
+		t.Fatalf("unexpected error releasing whole device: %v", err)
+	}
+}
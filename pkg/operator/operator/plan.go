@@ -0,0 +1,191 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kcorev1 "k8s.io/api/core/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// _nodeInstanceTypeLabel is the standard label the cluster autoscaler and cloud providers set on
+// every node to record its instance type.
+const _nodeInstanceTypeLabel = "node.kubernetes.io/instance-type"
+
+// PlanClusterAPIs bin-packs apis (the pending batch) alongside every API already deployed in the
+// cluster across the real node inventory reported by the Kubernetes API, and reports what it
+// would take to fit them: additional nodes per instance type (bounded by the cluster
+// autoscaler's configured max), APIs that can never fit on any of their eligible instance types,
+// cross-namespace endpoint collisions, and the estimated hourly cost delta of the batch. The
+// report type lives in pkg/operator/schema, not here, so the CLI can render it without importing
+// this package.
+func PlanClusterAPIs(apis []userconfig.API, projectFileMap map[string][]byte) (*schema.Plan, error) {
+	nodes, err := config.K8s.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	deployedAPIs, err := config.K8s.ListDeployedAPIs()
+	if err != nil {
+		return nil, err
+	}
+
+	virtualServices, err := config.K8s.ListVirtualServicesAllNamespaces(nil)
+	if err != nil {
+		return nil, err
+	}
+	gateways, err := listAllGatewayNames(virtualServices)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &schema.Plan{
+		AdditionalNodesByInstanceType: map[string]int{},
+	}
+
+	for i := range apis {
+		if err := spec.ValidateAPI(&apis[i], projectFileMap); err != nil {
+			return nil, err
+		}
+
+		if err := validateEndpointCollisionsForGateways(&apis[i], virtualServices, gateways); err != nil {
+			plan.EndpointCollisions = append(plan.EndpointCollisions, schema.EndpointCollision{
+				Endpoint: *apis[i].Endpoint,
+				APIName:  apis[i].Name,
+				Gateway:  "cross-namespace",
+			})
+		}
+	}
+
+	capacity := remainingReplicaCapacityByInstanceType(nodes)
+
+	for _, api := range append(deployedAPIs, apis...) {
+		replicas := 1
+		if api.Autoscaling != nil {
+			replicas = api.Autoscaling.MaxReplicas
+		}
+
+		if !packAPI(api, replicas, capacity, plan) {
+			plan.UnschedulableAPIs = append(plan.UnschedulableAPIs, api.Name)
+		}
+	}
+
+	plan.EstimatedHourlyCostDelta = estimateHourlyCostDelta(plan.AdditionalNodesByInstanceType)
+
+	return plan, nil
+}
+
+// packAPI tries to fit replicas of api onto one of its eligible instance types (in preference
+// order), consuming from capacity as it goes. If none of them has room, it asks for additional
+// nodes on the first eligible instance type that the autoscaler can actually grow; it returns
+// false only when no eligible instance type is configured at all or none can ever fit even at
+// the autoscaler's ceiling.
+func packAPI(api userconfig.API, replicas int, capacity map[string]int, plan *schema.Plan) bool {
+	if len(api.NodeGroups) == 0 {
+		return false
+	}
+
+	for _, instanceType := range api.NodeGroups {
+		if capacity[instanceType] >= replicas {
+			capacity[instanceType] -= replicas
+			return true
+		}
+	}
+
+	for _, instanceType := range api.NodeGroups {
+		shortfall := replicas - capacity[instanceType]
+		additional := planAdditionalNodes(instanceType, shortfall)
+		if additional < 0 {
+			continue
+		}
+
+		nodeGroup := config.Cluster.NodeGroupByInstanceType(instanceType)
+		if nodeGroup.CurrentNodes+plan.AdditionalNodesByInstanceType[instanceType]+additional > nodeGroup.MaxNodes {
+			continue
+		}
+		plan.AdditionalNodesByInstanceType[instanceType] += additional
+		capacity[instanceType] = additional*nodeGroup.ReplicasPerNode - shortfall
+		return true
+	}
+
+	return false
+}
+
+// remainingReplicaCapacityByInstanceType multiplies each instance type's node count by its
+// configured replicas-per-node, giving the number of api replicas that can still be scheduled
+// without adding nodes.
+func remainingReplicaCapacityByInstanceType(nodes []kcorev1.Node) map[string]int {
+	capacity := map[string]int{}
+
+	for _, node := range nodes {
+		instanceType := node.Labels[_nodeInstanceTypeLabel]
+		if instanceType == "" {
+			continue
+		}
+		nodeGroup := config.Cluster.NodeGroupByInstanceType(instanceType)
+		if nodeGroup == nil {
+			continue
+		}
+		capacity[instanceType] += nodeGroup.ReplicasPerNode
+	}
+
+	return capacity
+}
+
+// planAdditionalNodes returns how many more nodes of instanceType are needed to cover shortfall
+// more replicas, clamped to the cluster autoscaler's configured max nodes for that group. -1
+// means the demand can never be satisfied even at the autoscaler's ceiling.
+func planAdditionalNodes(instanceType string, shortfall int) int {
+	if shortfall <= 0 {
+		return 0
+	}
+
+	nodeGroup := config.Cluster.NodeGroupByInstanceType(instanceType)
+	if nodeGroup == nil || nodeGroup.ReplicasPerNode <= 0 {
+		return -1
+	}
+
+	additional := (shortfall + nodeGroup.ReplicasPerNode - 1) / nodeGroup.ReplicasPerNode
+	if nodeGroup.CurrentNodes+additional > nodeGroup.MaxNodes {
+		return -1
+	}
+	return additional
+}
+
+func estimateHourlyCostDelta(additionalNodesByInstanceType map[string]int) float64 {
+	var delta float64
+	for instanceType, count := range additionalNodesByInstanceType {
+		delta += float64(count) * config.Cluster.HourlyPriceForInstanceType(instanceType)
+	}
+	return delta
+}
+
+func listAllGatewayNames(virtualServices []kunstructured.Unstructured) ([]string, error) {
+	var gateways []string
+	for _, virtualService := range virtualServices {
+		vsGateways, err := k8s.ExtractVirtualServiceGateways(&virtualService)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, vsGateways.Slice()...)
+	}
+	return gateways, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+func TestPackAPICreditsRemainderAcrossSharedInstanceType(t *testing.T) {
+	config.Cluster = &clusterconfig.InternalConfig{
+		NodeGroups: []clusterconfig.NodeGroup{
+			{InstanceType: "m5.large", ReplicasPerNode: 4, CurrentNodes: 0, MaxNodes: 10, HourlyPrice: 0.1},
+		},
+	}
+
+	capacity := map[string]int{}
+	plan := &schema.Plan{AdditionalNodesByInstanceType: map[string]int{}}
+
+	apiA := userconfig.API{Name: "a", NodeGroups: []string{"m5.large"}}
+	if !packAPI(apiA, 3, capacity, plan) {
+		t.Fatal("expected api a to be schedulable")
+	}
+	if plan.AdditionalNodesByInstanceType["m5.large"] != 1 {
+		t.Fatalf("expected 1 additional node for m5.large, got %d", plan.AdditionalNodesByInstanceType["m5.large"])
+	}
+	// 1 additional node * 4 replicas/node - 3 shortfall = 1 replica of leftover capacity.
+	if capacity["m5.large"] != 1 {
+		t.Fatalf("expected 1 replica of leftover capacity, got %d", capacity["m5.large"])
+	}
+
+	apiB := userconfig.API{Name: "b", NodeGroups: []string{"m5.large"}}
+	if !packAPI(apiB, 1, capacity, plan) {
+		t.Fatal("expected api b to fit in the leftover capacity from api a")
+	}
+	// api b should have been satisfied entirely by the leftover capacity, so no further nodes
+	// should have been requested.
+	if plan.AdditionalNodesByInstanceType["m5.large"] != 1 {
+		t.Fatalf("expected api b to reuse leftover capacity without requesting more nodes, got %d additional", plan.AdditionalNodesByInstanceType["m5.large"])
+	}
+	if capacity["m5.large"] != 0 {
+		t.Fatalf("expected leftover capacity to be fully consumed, got %d", capacity["m5.large"])
+	}
+}
+
+func TestPackAPINoEligibleNodeGroups(t *testing.T) {
+	config.Cluster = &clusterconfig.InternalConfig{}
+
+	capacity := map[string]int{}
+	plan := &schema.Plan{AdditionalNodesByInstanceType: map[string]int{}}
+
+	api := userconfig.API{Name: "a"}
+	if packAPI(api, 1, capacity, plan) {
+		t.Fatal("expected api with no node groups to be unschedulable")
+	}
+}
+
+func TestPackAPIBeyondAutoscalerCeiling(t *testing.T) {
+	config.Cluster = &clusterconfig.InternalConfig{
+		NodeGroups: []clusterconfig.NodeGroup{
+			{InstanceType: "m5.large", ReplicasPerNode: 4, CurrentNodes: 0, MaxNodes: 1, HourlyPrice: 0.1},
+		},
+	}
+
+	capacity := map[string]int{}
+	plan := &schema.Plan{AdditionalNodesByInstanceType: map[string]int{}}
+
+	api := userconfig.API{Name: "a", NodeGroups: []string{"m5.large"}}
+	if packAPI(api, 100, capacity, plan) {
+		t.Fatal("expected api requesting more replicas than the autoscaler ceiling allows to be unschedulable")
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+// _slobakeCheckInterval is how often the SLO expression is re-evaluated during a canary or
+// blue-green bake window.
+const _slobakeCheckInterval = 15 * time.Second
+
+// Refresh rolls out a new revision of api using the requested strategy, streaming progress
+// events to onProgress as the rollout advances. For "rolling" it behaves like a plain restart;
+// "canary" and "blue-green" provision a shadow Deployment and shift traffic on the existing
+// Istio VirtualService before promoting or rolling back.
+func Refresh(api string, opts schema.RefreshOptions, onProgress func(schema.RefreshProgressEvent)) (schema.RefreshResponse, error) {
+	switch opts.Strategy {
+	case schema.RefreshStrategyRolling, "":
+		return refreshRolling(api, opts, onProgress)
+	case schema.RefreshStrategyCanary, schema.RefreshStrategyBlueGreen:
+		if opts.NewImage == "" {
+			return schema.RefreshResponse{}, ErrorRefreshMissingImage(opts.Strategy)
+		}
+		return refreshShiftTraffic(api, opts, onProgress)
+	default:
+		return schema.RefreshResponse{}, ErrorInvalidRefreshStrategy(opts.Strategy)
+	}
+}
+
+func refreshRolling(api string, opts schema.RefreshOptions, onProgress func(schema.RefreshProgressEvent)) (schema.RefreshResponse, error) {
+	onProgress(schema.RefreshProgressEvent{Stage: "restarting", PercentComplete: 0, Message: "restarting replicas"})
+
+	if err := config.K8s.RestartDeployment(api); err != nil {
+		return schema.RefreshResponse{}, err
+	}
+
+	onProgress(schema.RefreshProgressEvent{Stage: "restarting", PercentComplete: 100, Message: "all replicas restarted"})
+	return schema.RefreshResponse{Message: "restarted " + api}, nil
+}
+
+// refreshShiftTraffic provisions a shadow Deployment running the new image, then incrementally
+// shifts the api's VirtualService route weight toward it while watching readiness and the
+// configured SLO expression, promoting on success or rolling back on failure/timeout.
+func refreshShiftTraffic(api string, opts schema.RefreshOptions, onProgress func(schema.RefreshProgressEvent)) (schema.RefreshResponse, error) {
+	oldDeployment, err := config.K8s.GetDeployment(api)
+	if err != nil {
+		return schema.RefreshResponse{}, err
+	}
+
+	shadowName := api + "-shadow"
+	onProgress(schema.RefreshProgressEvent{Stage: "provisioning", PercentComplete: 0, Message: "provisioning shadow deployment " + shadowName})
+
+	shadowDeployment := oldDeployment.DeepCopy()
+	shadowDeployment.Name = shadowName
+	for i := range shadowDeployment.Spec.Template.Spec.Containers {
+		shadowDeployment.Spec.Template.Spec.Containers[i].Image = opts.NewImage
+	}
+	if err := config.K8s.ApplyDeployment(shadowDeployment); err != nil {
+		return schema.RefreshResponse{}, err
+	}
+
+	if err := config.K8s.WaitForDeploymentReady(shadowName); err != nil {
+		if opts.RollbackOnError {
+			_ = config.K8s.DeleteDeployment(shadowName)
+		}
+		return schema.RefreshResponse{}, errors.Wrap(err, "shadow deployment never became ready")
+	}
+
+	weight := opts.CanaryWeight
+	if opts.Strategy == schema.RefreshStrategyBlueGreen {
+		weight = 100
+	}
+
+	onProgress(schema.RefreshProgressEvent{Stage: "shifting", PercentComplete: 10, Message: "shifting traffic to shadow deployment"})
+	if err := shiftVirtualServiceWeight(api, shadowName, weight); err != nil {
+		return schema.RefreshResponse{}, err
+	}
+
+	if ok, err := bakeAndWatchSLO(api, opts, onProgress); err != nil || !ok {
+		if opts.RollbackOnError {
+			onProgress(schema.RefreshProgressEvent{Stage: "rolling-back", PercentComplete: 100, Message: "SLO check failed, rolling back"})
+			_ = shiftVirtualServiceWeight(api, shadowName, 0)
+			_ = config.K8s.DeleteDeployment(shadowName)
+			return schema.RefreshResponse{Message: api + " rolled back"}, err
+		}
+		return schema.RefreshResponse{}, err
+	}
+
+	onProgress(schema.RefreshProgressEvent{Stage: "promoting", PercentComplete: 90, Message: "promoting shadow deployment"})
+	if err := shiftVirtualServiceWeight(api, shadowName, 100); err != nil {
+		return schema.RefreshResponse{}, err
+	}
+	if err := config.K8s.DeleteDeployment(api); err != nil {
+		return schema.RefreshResponse{}, err
+	}
+	if err := config.K8s.RenameDeployment(shadowName, api); err != nil {
+		return schema.RefreshResponse{}, err
+	}
+
+	onProgress(schema.RefreshProgressEvent{Stage: "promoting", PercentComplete: 100, Message: "promoted new version"})
+	return schema.RefreshResponse{Message: api + " refreshed with " + opts.Strategy + " strategy"}, nil
+}
+
+// shiftVirtualServiceWeight adjusts the http.route.weight values on the api's VirtualService so
+// that weight percent of traffic goes to the shadow subset and the rest stays on the original,
+// then writes the updated VirtualService back to the cluster.
+func shiftVirtualServiceWeight(api string, shadowName string, weight int) error {
+	virtualService, err := config.K8s.GetVirtualService(api)
+	if err != nil {
+		return err
+	}
+
+	if err := k8s.SetVirtualServiceRouteWeights(virtualService, map[string]int{
+		api:        100 - weight,
+		shadowName: weight,
+	}); err != nil {
+		return err
+	}
+
+	_, err = config.K8s.UpdateVirtualService(virtualService)
+	return err
+}
+
+// bakeAndWatchSLO polls the configured Prometheus SLO expression (error rate, p95 latency) for
+// the duration of the bake window, returning false as soon as the expression is violated.
+func bakeAndWatchSLO(api string, opts schema.RefreshOptions, onProgress func(schema.RefreshProgressEvent)) (bool, error) {
+	deadline := time.Now().Add(opts.CanaryDuration)
+
+	for time.Now().Before(deadline) {
+		ok, err := config.Prometheus.CheckSLO(api)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+
+		remaining := time.Until(deadline)
+		percent := 10 + int(90*(opts.CanaryDuration-remaining)/opts.CanaryDuration)
+		onProgress(schema.RefreshProgressEvent{Stage: "baking", PercentComplete: percent, Message: "bake window in progress"})
+
+		time.Sleep(_slobakeCheckInterval)
+	}
+
+	return true, nil
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/lib/strset"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
@@ -41,12 +42,20 @@ func ValidateClusterAPIs(apis []userconfig.API, projectFileMap map[string][]byte
 		return err
 	}
 
+	extraValidators, err := registeredValidators()
+	if err != nil {
+		return err
+	}
+	validators := append([]Validator{builtinValidator{virtualServices: virtualServices, maxMem: maxMem}}, extraValidators...)
+
 	for i := range apis {
 		if err := spec.ValidateAPI(&apis[i], projectFileMap); err != nil {
 			return err
 		}
-		if err := ValidateK8s(&apis[i], config.Cluster, virtualServices, maxMem); err != nil {
-			return err
+		for _, validator := range validators {
+			if err := validator.Validate(&apis[i], apis); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -63,6 +72,9 @@ func ValidateClusterAPIs(apis []userconfig.API, projectFileMap map[string][]byte
 	return nil
 }
 
+// ValidateK8s runs only the built-in k8s-aware checks (compute limits, endpoint collisions)
+// against a single API. It is the check behind builtinValidator, the first validator in a
+// ValidateClusterAPIs pass.
 func ValidateK8s(api *userconfig.API,
 	config *clusterconfig.InternalConfig,
 	virtualServices []kunstructured.Unstructured,
@@ -100,6 +112,14 @@ func validateCompute(compute *userconfig.Compute, config *clusterconfig.Internal
 			return ErrorNoAvailableNodeComputeLimit("Memory", compute.Mem.String(), maxMem.String())
 		}
 	}
+
+	if compute.GPUMem != nil {
+		if compute.GPU > 0 {
+			return ErrorComputeGPUAndGPUMemMutuallyExclusive()
+		}
+		return validateGPUShare(&compute.GPUMem.Quantity)
+	}
+
 	if compute.GPU > maxGPU {
 		return ErrorNoAvailableNodeComputeLimit("GPU", fmt.Sprintf("%d", compute.GPU), fmt.Sprintf("%d", maxGPU))
 	}
@@ -107,12 +127,25 @@ func validateCompute(compute *userconfig.Compute, config *clusterconfig.Internal
 }
 
 func validateEndpointCollisions(api *userconfig.API, virtualServices []kunstructured.Unstructured) error {
+	return validateEndpointCollisionsForGateways(api, virtualServices, nil)
+}
+
+// validateEndpointCollisionsForGateways checks api's endpoint against every virtualService that
+// is attached to one of gateways. A nil/empty gateways restricts the check to "apis-gateway",
+// matching the single-deploy fast path; the capacity planner passes every gateway in the
+// cluster so it can catch cross-namespace collisions that the fast path would miss.
+func validateEndpointCollisionsForGateways(api *userconfig.API, virtualServices []kunstructured.Unstructured, gateways []string) error {
+	if len(gateways) == 0 {
+		gateways = []string{"apis-gateway"}
+	}
+	gatewaySet := strset.New(gateways...)
+
 	for _, virtualService := range virtualServices {
-		gateways, err := k8s.ExtractVirtualServiceGateways(&virtualService)
+		vsGateways, err := k8s.ExtractVirtualServiceGateways(&virtualService)
 		if err != nil {
 			return err
 		}
-		if !gateways.Has("apis-gateway") {
+		if !gatewaySet.HasAny(vsGateways.Slice()...) {
 			continue
 		}
 
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newCollisionTestVirtualService(apiName string, gateway string, endpoint string) kunstructured.Unstructured {
+	return kunstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{"apiName": apiName},
+			},
+			"spec": map[string]interface{}{
+				"gateways": []interface{}{gateway},
+				"http": []interface{}{
+					map[string]interface{}{
+						"match": []interface{}{
+							map[string]interface{}{"uri": map[string]interface{}{"prefix": endpoint}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestValidateEndpointCollisionsForGateways(t *testing.T) {
+	virtualServices := []kunstructured.Unstructured{
+		newCollisionTestVirtualService("existing-api", "team-a-gateway", "/my-api"),
+	}
+
+	api := &userconfig.API{Name: "new-api", Endpoint: strPtr("/my-api")}
+
+	// Restricting to the default fast-path gateway should miss a collision on another gateway.
+	if err := validateEndpointCollisionsForGateways(api, virtualServices, nil); err != nil {
+		t.Fatalf("expected no collision when the default gateway doesn't match, got: %v", err)
+	}
+
+	// Passing every gateway in the cluster (as the capacity planner does) should catch it.
+	if err := validateEndpointCollisionsForGateways(api, virtualServices, []string{"team-a-gateway"}); err == nil {
+		t.Fatal("expected a cross-namespace endpoint collision to be detected")
+	}
+}
+
+func TestValidateEndpointCollisionsForGatewaysIgnoresSameAPI(t *testing.T) {
+	virtualServices := []kunstructured.Unstructured{
+		newCollisionTestVirtualService("my-api", "apis-gateway", "/my-api"),
+	}
+
+	api := &userconfig.API{Name: "my-api", Endpoint: strPtr("/my-api")}
+
+	if err := validateEndpointCollisionsForGateways(api, virtualServices, []string{"apis-gateway"}); err != nil {
+		t.Fatalf("expected no collision against the api's own virtualservice, got: %v", err)
+	}
+}
+
+func TestValidateEndpointCollisionsForGatewaysNoMatch(t *testing.T) {
+	virtualServices := []kunstructured.Unstructured{
+		newCollisionTestVirtualService("existing-api", "apis-gateway", "/other-endpoint"),
+	}
+
+	api := &userconfig.API{Name: "new-api", Endpoint: strPtr("/my-api")}
+
+	if err := validateEndpointCollisionsForGateways(api, virtualServices, []string{"apis-gateway"}); err != nil {
+		t.Fatalf("expected no collision for a distinct endpoint, got: %v", err)
+	}
+}
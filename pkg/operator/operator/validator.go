@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/policy"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Validator is implemented by anything that can reject an API as part of a ValidateClusterAPIs
+// pass. api is the one currently being checked; apis is the full pending batch, so a validator
+// can reason about collisions within the batch as well as against the one API. The built-in
+// checks (compute limits, endpoint collisions) are one implementation, while duplicate names and
+// endpoints within the batch are checked separately by ValidateClusterAPIs; webhook and
+// OPA-backed validators registered via clusterconfig let teams enforce org policy without
+// forking Cortex. It's an alias of policy.Validator so the CLI can run the same OPA check locally
+// without importing this package.
+type Validator = policy.Validator
+
+// builtinValidator wraps the existing compute/endpoint checks so they fit the Validator
+// interface alongside externally registered validators.
+type builtinValidator struct {
+	virtualServices []kunstructured.Unstructured
+	maxMem          *kresource.Quantity
+}
+
+func (v builtinValidator) Validate(api *userconfig.API, apis []userconfig.API) error {
+	return ValidateK8s(api, config.Cluster, v.virtualServices, v.maxMem)
+}
+
+// registeredValidators builds the chain of externally configured validators (webhooks, OPA
+// policies) from the cluster config. An empty chain means no additional policies are enforced.
+// A policy file that fails to load is a misconfiguration, not something to silently skip, so it
+// is surfaced as an error rather than dropped from the chain.
+func registeredValidators() ([]Validator, error) {
+	var validators []Validator
+
+	for _, webhook := range config.Cluster.ValidatingWebhooks {
+		validators = append(validators, webhookValidator{endpoint: webhook.Endpoint, failurePolicy: webhook.FailurePolicy})
+	}
+
+	for _, policyFile := range config.Cluster.OPAPolicyFiles {
+		validator, err := policy.NewOPAValidator(policyFile)
+		if err != nil {
+			return nil, err
+		}
+		validators = append(validators, validator)
+	}
+
+	return validators, nil
+}
+
+// RunValidatorChain runs every registered validator (built-in checks excluded) against each api
+// in apis, stopping at the first failure and wrapping it with the api's identity through
+// errors.Wrap the same way per-field validation does. This
+// backs the operator's /validate endpoint, which `cortex validate` submits to after running any
+// CLI-local policy file check of its own. The registered chain is built once and reused across
+// all apis rather than per-API, since building it re-reads and recompiles every configured OPA
+// policy file.
+func RunValidatorChain(apis []userconfig.API, extra ...Validator) error {
+	validators, err := registeredValidators()
+	if err != nil {
+		return err
+	}
+	validators = append(validators, extra...)
+
+	for i := range apis {
+		for _, validator := range validators {
+			if err := validator.Validate(&apis[i], apis); err != nil {
+				return errors.Wrap(err, apis[i].Identify())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// stubValidator rejects every api whose name is in reject, recording which apis it was asked to
+// check so the test can confirm the chain stops on the first failure.
+type stubValidator struct {
+	reject string
+	calls  *[]string
+}
+
+func (v stubValidator) Validate(api *userconfig.API, apis []userconfig.API) error {
+	*v.calls = append(*v.calls, api.Name)
+	if api.Name == v.reject {
+		return fmt.Errorf("%s rejected by stub policy", api.Name)
+	}
+	return nil
+}
+
+func TestRunValidatorChainPassesExtraValidators(t *testing.T) {
+	config.Cluster = &clusterconfig.InternalConfig{}
+
+	apis := []userconfig.API{{Name: "a"}, {Name: "b"}}
+	var calls []string
+
+	err := RunValidatorChain(apis, stubValidator{reject: "", calls: &calls})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("expected every api to be checked, got %v", calls)
+	}
+}
+
+func TestRunValidatorChainStopsOnFirstFailure(t *testing.T) {
+	config.Cluster = &clusterconfig.InternalConfig{}
+
+	apis := []userconfig.API{{Name: "a"}, {Name: "b"}}
+	var calls []string
+
+	err := RunValidatorChain(apis, stubValidator{reject: "a", calls: &calls})
+	if err == nil {
+		t.Fatal("expected an error from the rejecting validator")
+	}
+	if len(calls) != 1 {
+		t.Fatalf("expected the chain to stop after the first rejected api, got %v", calls)
+	}
+}
+
+func TestRunValidatorChainRunsMultipleValidatorsPerAPI(t *testing.T) {
+	config.Cluster = &clusterconfig.InternalConfig{}
+
+	apis := []userconfig.API{{Name: "a"}}
+	var firstCalls, secondCalls []string
+
+	err := RunValidatorChain(apis,
+		stubValidator{reject: "", calls: &firstCalls},
+		stubValidator{reject: "", calls: &secondCalls},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(firstCalls) != 1 || len(secondCalls) != 1 {
+		t.Fatalf("expected both validators to run against api a, got %v and %v", firstCalls, secondCalls)
+	}
+}
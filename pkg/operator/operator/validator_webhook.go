@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+const _webhookTimeout = 10 * time.Second
+
+// webhookValidator POSTs the parsed userconfig.API, plus a small amount of cluster context, to
+// an out-of-process ValidatingAdmissionWebhook-style endpoint and fails the API if the webhook
+// rejects it.
+type webhookValidator struct {
+	endpoint      string
+	failurePolicy string // "Fail" or "Ignore", mirroring k8s admission webhook semantics
+}
+
+type webhookValidationRequest struct {
+	API         *userconfig.API `json:"api"`
+	ClusterName string          `json:"cluster_name"`
+	Provider    string          `json:"provider"`
+	Region      string          `json:"region"`
+}
+
+type webhookValidationResponse struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+func (v webhookValidator) Validate(api *userconfig.API, apis []userconfig.API) error {
+	body, err := json.Marshal(webhookValidationRequest{
+		API:         api,
+		ClusterName: config.Cluster.ClusterName,
+		Provider:    config.Cluster.Provider,
+		Region:      config.Cluster.Region,
+	})
+	if err != nil {
+		return errors.Wrap(err, v.endpoint)
+	}
+
+	client := http.Client{Timeout: _webhookTimeout}
+	resp, err := client.Post(v.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		if v.failurePolicy == "Ignore" {
+			return nil
+		}
+		return errors.Wrap(err, v.endpoint)
+	}
+	defer resp.Body.Close()
+
+	var result webhookValidationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		if v.failurePolicy == "Ignore" {
+			return nil
+		}
+		return errors.Wrap(err, v.endpoint)
+	}
+
+	if !result.Allowed {
+		return ErrorValidatingWebhookRejected(v.endpoint, result.Reason)
+	}
+
+	return nil
+}
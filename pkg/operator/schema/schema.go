@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema holds the request/response types shared between the CLI and the operator's
+// HTTP API, so the CLI never needs to import the operator's internal packages directly.
+package schema
+
+import "time"
+
+// RefreshResponse is returned by the operator after a refresh request is accepted or completes.
+type RefreshResponse struct {
+	Message string `json:"message"`
+}
+
+const (
+	RefreshStrategyRolling   = "rolling"
+	RefreshStrategyCanary    = "canary"
+	RefreshStrategyBlueGreen = "blue-green"
+)
+
+// RefreshOptions carries the `cortex refresh` flags through to the operator. NewImage is the
+// image the shadow deployment should run; it's required for canary/blue-green (there's nothing
+// to shift traffic to otherwise) and ignored for a rolling restart, which just restarts the
+// replicas already running the api's current image.
+type RefreshOptions struct {
+	Force           bool          `json:"force"`
+	Strategy        string        `json:"strategy"`
+	NewImage        string        `json:"new_image"`
+	CanaryWeight    int           `json:"canary_weight"`
+	CanaryDuration  time.Duration `json:"canary_duration"`
+	RollbackOnError bool          `json:"rollback_on_error"`
+}
+
+// RefreshProgressEvent is one update in the stream the operator sends back while a rollout
+// bakes, so the CLI can render a live progress bar.
+type RefreshProgressEvent struct {
+	Stage           string `json:"stage"`
+	PercentComplete int    `json:"percent_complete"`
+	Message         string `json:"message"`
+}
+
+// Plan is the capacity planner's report for a batch of apis, returned by `cortex plan` and the
+// operator's /plan endpoint.
+type Plan struct {
+	AdditionalNodesByInstanceType map[string]int      `json:"additional_nodes_by_instance_type"`
+	UnschedulableAPIs             []string            `json:"unschedulable_apis"`
+	EndpointCollisions            []EndpointCollision `json:"endpoint_collisions"`
+	EstimatedHourlyCostDelta      float64             `json:"estimated_hourly_cost_delta"`
+}
+
+// EndpointCollision reports two APIs, possibly in different namespaces, that would route the
+// same endpoint on the same gateway.
+type EndpointCollision struct {
+	Endpoint string `json:"endpoint"`
+	APIName  string `json:"api_name"`
+	Gateway  string `json:"gateway"`
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+import (
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// InstanceMetadata describes the compute resources available on a single node of the cluster's
+// (currently single) instance type.
+type InstanceMetadata struct {
+	CPU kresource.Quantity `json:"cpu"`
+	Mem kresource.Quantity `json:"mem"`
+	GPU int64              `json:"gpu"`
+}
+
+// WebhookValidator configures an out-of-process ValidatingAdmissionWebhook-style endpoint that
+// ValidateClusterAPIs POSTs every api to.
+type WebhookValidator struct {
+	Endpoint      string `json:"endpoint" yaml:"endpoint"`
+	FailurePolicy string `json:"failure_policy" yaml:"failure_policy"` // "Fail" or "Ignore"
+}
+
+// SLOConfig sets the error-rate and p95-latency thresholds a canary/blue-green bake window
+// checks against; crossing either aborts the bake instead of promoting.
+type SLOConfig struct {
+	MaxErrorRate      float64 `json:"max_error_rate" yaml:"max_error_rate"`
+	MaxP95LatencySecs float64 `json:"max_p95_latency_seconds" yaml:"max_p95_latency_seconds"`
+}
+
+// NodeGroup is one of the cluster autoscaler's managed node groups.
+type NodeGroup struct {
+	InstanceType    string  `json:"instance_type" yaml:"instance_type"`
+	ReplicasPerNode int     `json:"replicas_per_node" yaml:"replicas_per_node"`
+	CurrentNodes    int     `json:"current_nodes" yaml:"current_nodes"`
+	MaxNodes        int     `json:"max_nodes" yaml:"max_nodes"`
+	HourlyPrice     float64 `json:"hourly_price" yaml:"hourly_price"`
+}
+
+// InternalConfig is the operator's in-cluster view of the cluster it is managing.
+type InternalConfig struct {
+	ClusterName        string             `json:"cluster_name" yaml:"cluster_name"`
+	Provider           string             `json:"provider" yaml:"provider"`
+	Region             string             `json:"region" yaml:"region"`
+	InstanceMetadata   InstanceMetadata   `json:"instance_metadata" yaml:"instance_metadata"`
+	NodeGroups         []NodeGroup        `json:"node_groups" yaml:"node_groups"`
+	ValidatingWebhooks []WebhookValidator `json:"validating_webhooks" yaml:"validating_webhooks"`
+	OPAPolicyFiles     []string           `json:"opa_policy_files" yaml:"opa_policy_files"`
+	SLO                SLOConfig          `json:"slo" yaml:"slo"`
+}
+
+// NodeGroupByInstanceType returns the configured node group for instanceType, or nil if the
+// cluster autoscaler doesn't manage that instance type.
+func (c *InternalConfig) NodeGroupByInstanceType(instanceType string) *NodeGroup {
+	for i := range c.NodeGroups {
+		if c.NodeGroups[i].InstanceType == instanceType {
+			return &c.NodeGroups[i]
+		}
+	}
+	return nil
+}
+
+// HourlyPriceForInstanceType returns the configured on-demand hourly price for instanceType, or
+// 0 if it isn't a managed node group.
+func (c *InternalConfig) HourlyPriceForInstanceType(instanceType string) float64 {
+	if nodeGroup := c.NodeGroupByInstanceType(instanceType); nodeGroup != nil {
+		return nodeGroup.HourlyPrice
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package types holds the handful of enums shared across the CLI and the operator that don't
+// belong to either side's own request/response schema.
+package types
+
+// ProviderType identifies which provider backs an environment: a local Docker-based cluster, or
+// a real cloud-hosted one reachable through its operator.
+type ProviderType string
+
+const (
+	LocalProviderType ProviderType = "local"
+	AWSProviderType   ProviderType = "aws"
+)
+
+func (t ProviderType) String() string {
+	return string(t)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spec validates the parsed, in-memory representation of a cortex api config (as opposed
+// to operator/ValidateK8s, which checks an api against live cluster state).
+package spec
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// ValidateAPI runs the static checks that don't require talking to the cluster (field presence,
+// project file references, etc.).
+func ValidateAPI(api *userconfig.API, projectFileMap map[string][]byte) error {
+	if api.Name == "" {
+		return fmt.Errorf("api is missing a name")
+	}
+	if api.Endpoint == nil {
+		return fmt.Errorf("%s: endpoint is required", api.Identify())
+	}
+	return nil
+}
+
+func ErrorNoAPIs() error {
+	return fmt.Errorf("at least one api must be defined")
+}
+
+func ErrorDuplicateName(apis []userconfig.API) error {
+	return fmt.Errorf("name %q is defined in more than one api", apis[0].Name)
+}
+
+func ErrorDuplicateEndpointInOneDeploy(apis []userconfig.API) error {
+	return fmt.Errorf("endpoint %q is defined in more than one api", *apis[0].Endpoint)
+}
+
+func ErrorDuplicateEndpoint(apiName string) error {
+	return fmt.Errorf("endpoint is already in use by api %q", apiName)
+}
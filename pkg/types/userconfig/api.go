@@ -0,0 +1,44 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+import "fmt"
+
+const (
+	ComputeKey  = "compute"
+	EndpointKey = "endpoint"
+)
+
+// Autoscaling controls how many replicas of an api are scheduled.
+type Autoscaling struct {
+	MinReplicas int `json:"min_replicas" yaml:"min_replicas"`
+	MaxReplicas int `json:"max_replicas" yaml:"max_replicas"`
+}
+
+// API is the parsed representation of a single api definition in a cortex config file.
+type API struct {
+	Name        string       `json:"name" yaml:"name"`
+	Endpoint    *string      `json:"endpoint" yaml:"endpoint"`
+	Compute     *Compute     `json:"compute" yaml:"compute"`
+	Autoscaling *Autoscaling `json:"autoscaling" yaml:"autoscaling"`
+	NodeGroups  []string     `json:"node_groups" yaml:"node_groups"`
+}
+
+// Identify returns a short string identifying the api in error messages.
+func (api *API) Identify() string {
+	return fmt.Sprintf("api %s", api.Name)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+import (
+	kresource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Quantity wraps a Kubernetes resource.Quantity so compute requests round-trip through the YAML
+// api config the same way the rest of userconfig does.
+type Quantity struct {
+	kresource.Quantity
+}
+
+// UnmarshalYAML parses a scalar like "200m" or "4Gi" into the embedded kresource.Quantity.
+// kresource.Quantity only implements the JSON marshaling interfaces, which gopkg.in/yaml.v2 never
+// calls, and its fields are all unexported, so without this every compute field (not just
+// gpu_mem) would silently come back zero-valued from the api config.
+func (q *Quantity) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+
+	parsed, err := kresource.ParseQuantity(str)
+	if err != nil {
+		return err
+	}
+
+	q.Quantity = parsed
+	return nil
+}
+
+// MarshalYAML renders the quantity the same way it was written, e.g. "200m" or "4Gi".
+func (q Quantity) MarshalYAML() (interface{}, error) {
+	return q.String(), nil
+}
+
+// Compute specifies the resources requested for a single api replica. GPU and GPUMem are
+// mutually exclusive: GPU requests a whole device, while GPUMem requests a memory-bounded slice
+// of a device that may be time-shared with other apis.
+type Compute struct {
+	CPU    Quantity  `json:"cpu" yaml:"cpu"`
+	Mem    *Quantity `json:"mem" yaml:"mem"`
+	GPU    int64     `json:"gpu" yaml:"gpu"`
+	GPUMem *Quantity `json:"gpu_mem" yaml:"gpu_mem"`
+	Inf    int64     `json:"inf" yaml:"inf"`
+}
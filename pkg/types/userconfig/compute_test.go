@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestQuantityUnmarshalYAML(t *testing.T) {
+	var compute Compute
+	err := yaml.Unmarshal([]byte("cpu: 200m\ngpu: 0\ngpu_mem: 4Gi\ninf: 0\n"), &compute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if compute.CPU.String() != "200m" {
+		t.Errorf("expected cpu to be 200m, got %s", compute.CPU.String())
+	}
+	if compute.GPUMem == nil {
+		t.Fatal("expected gpu_mem to be populated")
+	}
+	if compute.GPUMem.String() != "4Gi" {
+		t.Errorf("expected gpu_mem to be 4Gi, got %s", compute.GPUMem.String())
+	}
+}
+
+func TestQuantityUnmarshalYAMLInvalid(t *testing.T) {
+	var compute Compute
+	err := yaml.Unmarshal([]byte("cpu: not-a-quantity\n"), &compute)
+	if err == nil {
+		t.Fatal("expected an error for an invalid quantity")
+	}
+}
+
+func TestQuantityMarshalYAML(t *testing.T) {
+	var compute Compute
+	if err := yaml.Unmarshal([]byte("cpu: 500m\n"), &compute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := yaml.Marshal(&compute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var roundTripped Compute
+	if err := yaml.Unmarshal(out, &roundTripped); err != nil {
+		t.Fatalf("unexpected error round-tripping: %v", err)
+	}
+	if roundTripped.CPU.String() != "500m" {
+		t.Errorf("expected cpu to round-trip to 500m, got %s", roundTripped.CPU.String())
+	}
+}